@@ -1,34 +1,70 @@
 package main
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"encoding/hex"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"sort"
 	"strings"
+	"sync/atomic"
 
-	"github.com/decred/dcrd/hdkeychain/v3"
 	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/accounts/usbwallet"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/tyler-smith/go-bip39"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/maurelian/contracts-1/internal/hdwallet"
+	"golang.org/x/term"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "verify" {
+		runVerify(os.Args[2:])
+		return
+	}
+
 	var privateKey string
 	var ledger bool
+	var trezor bool
 	var mnemonic string
-	var hdPath string
+	var mnemonicPassphrase string
+	var keystoreFile string
+	var clefEndpoint string
+	var hdPaths string
+	var listAccounts int
+	var typedDataMode bool
+	var collect int
 	flag.StringVar(&privateKey, "private-key", "", "Private key to use for signing")
 	flag.BoolVar(&ledger, "ledger", false, "Use ledger device for signing")
+	flag.BoolVar(&trezor, "trezor", false, "Use Trezor device for signing")
 	flag.StringVar(&mnemonic, "mnemonic", "", "Mnemonic to use for signing")
-	flag.StringVar(&hdPath, "hd-paths", "m/44'/60'/0'/0/0", "Hierarchical deterministic derivation path for mnemonic or ledger")
+	flag.StringVar(&mnemonicPassphrase, "mnemonic-passphrase", "", "Optional BIP-39 passphrase for --mnemonic")
+	flag.StringVar(&keystoreFile, "keystore", "", "Path to a V3 keystore JSON file to use for signing")
+	flag.StringVar(&clefEndpoint, "clef", "", "Endpoint (HTTP or IPC) of a clef external signer to use for signing")
+	flag.StringVar(&hdPaths, "hd-paths", "m/44'/60'/0'/0/0", "Comma-separated hierarchical deterministic derivation path(s) for mnemonic, ledger, or trezor")
+	flag.IntVar(&listAccounts, "list-accounts", 0, "List the first N addresses under m/44'/60'/0'/0 for --mnemonic and exit")
+	flag.BoolVar(&typedDataMode, "typed-data", false, "Read a full EIP-712 JSON payload (domain, types, primaryType, message) from stdin instead of a raw hash")
+	flag.IntVar(&collect, "collect", 0, "Aggregate mode: collect N signatures from distinct --ledger/--trezor accounts or --mnemonic HD indices and emit a packed Safe multisig blob")
 	flag.Parse()
 
+	if listAccounts > 0 {
+		listMnemonicAccounts(mnemonic, mnemonicPassphrase, listAccounts)
+		return
+	}
+
+	if collect > 0 {
+		runAggregate(mnemonic, mnemonicPassphrase, ledger, trezor, hdPaths, collect, typedDataMode)
+		return
+	}
+
 	options := 0
 	if privateKey != "" {
 		options++
@@ -36,11 +72,38 @@ func main() {
 	if ledger {
 		options++
 	}
+	if trezor {
+		options++
+	}
 	if mnemonic != "" {
 		options++
 	}
+	if keystoreFile != "" {
+		options++
+	}
+	if clefEndpoint != "" {
+		options++
+	}
 	if options != 1 {
-		log.Fatalf("One (and only one) of --private-key, --ledger, --mnemonic must be set")
+		log.Fatalf("One (and only one) of --private-key, --ledger, --trezor, --mnemonic, --keystore, --clef must be set")
+	}
+
+	paths, err := parseHDPaths(hdPaths)
+	if err != nil {
+		log.Fatalf("Error parsing --hd-paths: %v", err)
+	}
+	if len(paths) != 1 {
+		log.Fatalf("Expected exactly 1 path in --hd-paths, got %d", len(paths))
+	}
+
+	s, err := createSigner(privateKey, mnemonic, mnemonicPassphrase, keystoreFile, clefEndpoint, trezor, paths[0])
+	if err != nil {
+		log.Fatalf("Error creating signer: %v", err)
+	}
+
+	if typedDataMode {
+		signTypedDataMode(s)
+		return
 	}
 
 	bytes, err := io.ReadAll(os.Stdin)
@@ -53,11 +116,6 @@ func main() {
 		log.Fatalf("Expected EIP-712 hex string with 66 bytes, got %d bytes, value: %s", len(bytes), string(bytes))
 	}
 
-	s, err := createSigner(privateKey, mnemonic, hdPath)
-	if err != nil {
-		log.Fatalf("Error creating signer: %v", err)
-	}
-
 	signature, err := s.sign(hash)
 	if err != nil {
 		log.Fatalf("Error signing data: %v", err)
@@ -66,14 +124,262 @@ func main() {
 	fmt.Printf("Data: %s\n", hex.EncodeToString(hash))
 	fmt.Printf("Signer: %s\n", s.address().String())
 	fmt.Printf("Signature: %s\n", hex.EncodeToString(signature))
+	printLocalSequenceNumber(s)
 }
 
-func createSigner(privateKey, mnemonic, hdPath string) (signer, error) {
-	path, err := accounts.ParseDerivationPath(hdPath)
+// signTypedDataMode reads a full EIP-712 JSON payload from stdin and signs it,
+// so Ledger (and other wallet-backed) users are shown the domain/message
+// fields instead of a blind hash.
+func signTypedDataMode(s signer) {
+	raw, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		log.Fatalf("Error reading from stdin: %v", err)
+	}
+
+	var typedData apitypes.TypedData
+	if err := json.Unmarshal(raw, &typedData); err != nil {
+		log.Fatalf("Error parsing EIP-712 JSON payload: %v", err)
+	}
+
+	domainSeparator, messageHash, digest, err := hdwallet.HashTypedData(&typedData)
+	if err != nil {
+		log.Fatalf("Error hashing typed data: %v", err)
+	}
+
+	signature, err := s.signTypedData(&typedData)
+	if err != nil {
+		log.Fatalf("Error signing typed data: %v", err)
+	}
+
+	fmt.Printf("Domain separator: %s\n", hex.EncodeToString(domainSeparator))
+	fmt.Printf("Message hash: %s\n", hex.EncodeToString(messageHash))
+	fmt.Printf("Data: %s\n", hex.EncodeToString(digest))
+	fmt.Printf("Signer: %s\n", s.address().String())
+	fmt.Printf("Signature: %s\n", hex.EncodeToString(signature))
+	printLocalSequenceNumber(s)
+}
+
+// localSequenceNumberer is implemented by signers that can surface a local,
+// process-global sequence number for the last request they sent to a remote
+// signing backend. It is a tool-side counter only, not an identifier the
+// backend itself assigns, so it cannot be cross-referenced against e.g.
+// clef's own audit log; it's useful only for correlating this tool's own
+// output across a single run.
+type localSequenceNumberer interface {
+	lastSequenceNumber() (uint64, bool)
+}
+
+func printLocalSequenceNumber(s signer) {
+	seqer, ok := s.(localSequenceNumberer)
+	if !ok {
+		return
+	}
+	if n, ok := seqer.lastSequenceNumber(); ok {
+		fmt.Printf("Local request #: %d\n", n)
+	}
+}
+
+// parseHDPaths parses a comma-separated list of hierarchical deterministic
+// derivation paths, e.g. "m/44'/60'/0'/0/0,m/44'/60'/0'/0/1".
+func parseHDPaths(hdPaths string) ([]accounts.DerivationPath, error) {
+	raw := strings.Split(hdPaths, ",")
+	paths := make([]accounts.DerivationPath, 0, len(raw))
+	for _, p := range raw {
+		path, err := accounts.ParseDerivationPath(strings.TrimSpace(p))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing path %q: %w", p, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// listMnemonicAccounts prints the first n addresses under
+// hdwallet.DefaultBasePath derived from mnemonic, so operators can pick the
+// right index before signing.
+func listMnemonicAccounts(mnemonic, passphrase string, n int) {
+	if mnemonic == "" {
+		log.Fatalf("--list-accounts requires --mnemonic")
+	}
+
+	w, err := hdwallet.New(mnemonic, passphrase)
+	if err != nil {
+		log.Fatalf("Error loading mnemonic: %v", err)
+	}
+
+	base, err := accounts.ParseDerivationPath(hdwallet.DefaultBasePath)
+	if err != nil {
+		log.Fatalf("Error parsing base derivation path: %v", err)
+	}
+
+	accts, err := w.Accounts(base, n)
+	if err != nil {
+		log.Fatalf("Error deriving accounts: %v", err)
+	}
+
+	for _, acct := range accts {
+		fmt.Printf("%s: %s\n", acct.Path.String(), acct.Address.String())
+	}
+}
+
+// runAggregate collects a signature from each of N distinct accounts
+// (Ledger/Trezor indices or mnemonic HD indices) over the same stdin
+// payload, then packs them via packSafeSignatures into the blob Safe's
+// checkNSignatures expects.
+func runAggregate(mnemonic, mnemonicPassphrase string, ledger, trezor bool, hdPaths string, n int, typedDataMode bool) {
+	if mnemonic == "" && !ledger && !trezor {
+		log.Fatalf("--collect requires --mnemonic, --ledger, or --trezor")
+	}
+
+	paths, err := parseHDPaths(hdPaths)
+	if err != nil {
+		log.Fatalf("Error parsing --hd-paths: %v", err)
+	}
+	paths, err = expandHDPaths(paths, n)
+	if err != nil {
+		log.Fatalf("Error expanding --hd-paths for --collect: %v", err)
+	}
+
+	signers, err := collectSigners(mnemonic, mnemonicPassphrase, ledger, trezor, paths)
+	if err != nil {
+		log.Fatalf("Error collecting signers: %v", err)
+	}
+
+	var hash []byte
+	var typedData apitypes.TypedData
+	raw, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		log.Fatalf("Error reading from stdin: %v", err)
+	}
+	if typedDataMode {
+		if err := json.Unmarshal(raw, &typedData); err != nil {
+			log.Fatalf("Error parsing EIP-712 JSON payload: %v", err)
+		}
+	} else {
+		hash = common.FromHex(strings.TrimSpace(string(raw)))
+		if len(hash) != 66 {
+			log.Fatalf("Expected EIP-712 hex string with 66 bytes, got %d bytes, value: %s", len(raw), string(raw))
+		}
+	}
+
+	entries := make([]signedEntry, len(signers))
+	for i, s := range signers {
+		var sig []byte
+		var err error
+		if typedDataMode {
+			sig, err = s.signTypedData(&typedData)
+		} else {
+			sig, err = s.sign(hash)
+		}
+		if err != nil {
+			log.Fatalf("Error collecting signature %d/%d: %v", i+1, len(signers), err)
+		}
+		entries[i] = signedEntry{address: s.address(), sig: sig}
+	}
+
+	packed, manifest := packSafeSignatures(entries)
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		log.Fatalf("Error marshaling manifest: %v", err)
+	}
+
+	fmt.Printf("Signers: %d\n", len(entries))
+	fmt.Printf("Packed signatures: %s\n", hex.EncodeToString(packed))
+	fmt.Printf("Manifest:\n%s\n", manifestJSON)
+}
+
+// signedEntry pairs a signature with the address that produced it, so
+// packSafeSignatures can sort entries by address before packing.
+type signedEntry struct {
+	address common.Address
+	sig     []byte
+}
+
+// packSafeSignatures sorts entries by signer address ascending and packs
+// them into the concatenated 65-byte-per-signer blob Safe's
+// checkNSignatures expects. Every signer in this tool signs the raw or
+// EIP-712 digest directly rather than an eth_sign-prefixed message, so
+// recovery IDs are left as the plain v=27/28 this tool's signers already
+// produce (Safe's "default" branch) rather than bumped by 4, which would
+// tell checkNSignatures to re-hash with the eth_sign prefix and recover
+// the wrong address. It also returns a human-readable
+// address-to-signature manifest for the same entries.
+func packSafeSignatures(entries []signedEntry) (packed []byte, manifest map[string]string) {
+	sorted := append([]signedEntry{}, entries...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].address.Bytes(), sorted[j].address.Bytes()) < 0
+	})
+
+	packed = make([]byte, 0, 65*len(sorted))
+	manifest = make(map[string]string, len(sorted))
+	for _, e := range sorted {
+		packed = append(packed, e.sig...)
+		manifest[e.address.String()] = "0x" + hex.EncodeToString(e.sig)
+	}
+	return packed, manifest
+}
+
+// expandHDPaths returns n derivation paths: paths unchanged if it already
+// has n entries, or paths[0] repeated with its final component incremented
+// 0..n-1 (following BIP-44's ".../0/i" convention) if it has exactly one.
+func expandHDPaths(paths []accounts.DerivationPath, n int) ([]accounts.DerivationPath, error) {
+	if len(paths) == n {
+		return paths, nil
+	}
+	if len(paths) != 1 {
+		return nil, fmt.Errorf("expected 1 or %d paths in --hd-paths, got %d", n, len(paths))
+	}
+
+	base := paths[0]
+	if len(base) == 0 {
+		return nil, fmt.Errorf("path %v has no components to increment for --collect", base)
+	}
+	expanded := make([]accounts.DerivationPath, n)
+	for i := 0; i < n; i++ {
+		path := append(accounts.DerivationPath{}, base[:len(base)-1]...)
+		path = append(path, base[len(base)-1]+uint32(i))
+		expanded[i] = path
+	}
+	return expanded, nil
+}
+
+// collectSigners derives one signer per path, reusing a single mnemonic
+// wallet or hardware-wallet connection across all of them.
+func collectSigners(mnemonic, mnemonicPassphrase string, ledger, trezor bool, paths []accounts.DerivationPath) ([]signer, error) {
+	if mnemonic != "" {
+		w, err := hdwallet.New(mnemonic, mnemonicPassphrase)
+		if err != nil {
+			return nil, fmt.Errorf("error loading mnemonic: %w", err)
+		}
+		signers := make([]signer, len(paths))
+		for i, path := range paths {
+			key, err := w.Derive(path)
+			if err != nil {
+				return nil, fmt.Errorf("error deriving key for path %v: %w", path, err)
+			}
+			signers[i] = &ecdsaSigner{key}
+		}
+		return signers, nil
+	}
+
+	wallet, device, err := openHubDevice(trezor)
 	if err != nil {
 		return nil, err
 	}
 
+	signers := make([]signer, len(paths))
+	for i, path := range paths {
+		account, err := wallet.Derive(path, true)
+		if err != nil {
+			return nil, fmt.Errorf("error deriving %s account for path %v: %w", device, path, err)
+		}
+		signers[i] = &walletSigner{wallet: wallet, account: account}
+	}
+	return signers, nil
+}
+
+func createSigner(privateKey, mnemonic, mnemonicPassphrase, keystoreFile, clefEndpoint string, trezor bool, path accounts.DerivationPath) (signer, error) {
 	if privateKey != "" {
 		key, err := crypto.HexToECDSA(privateKey)
 		if err != nil {
@@ -83,31 +389,69 @@ func createSigner(privateKey, mnemonic, hdPath string) (signer, error) {
 	}
 
 	if mnemonic != "" {
-		key, err := derivePrivateKey(mnemonic, path)
+		w, err := hdwallet.New(mnemonic, mnemonicPassphrase)
+		if err != nil {
+			return nil, fmt.Errorf("error loading mnemonic: %w", err)
+		}
+		key, err := w.Derive(path)
 		if err != nil {
 			return nil, fmt.Errorf("error deriving key from mnemonic: %w", err)
 		}
 		return &ecdsaSigner{key}, nil
 	}
 
-	// assume using a ledger
-	ledgerHub, err := usbwallet.NewLedgerHub()
+	if keystoreFile != "" {
+		return openKeystore(keystoreFile)
+	}
+
+	if clefEndpoint != "" {
+		return openClefSigner(clefEndpoint)
+	}
+
+	wallet, device, err := openHubDevice(trezor)
+	if err != nil {
+		return nil, err
+	}
+	return openHubWallet(wallet, device, path)
+}
+
+// openHubDevice opens the single wallet exposed by a Trezor (if trezor is
+// set) or Ledger USB hub.
+func openHubDevice(trezor bool) (accounts.Wallet, string, error) {
+	device := "ledger"
+	var hub *usbwallet.Hub
+	var err error
+	if trezor {
+		device = "trezor"
+		hub, err = usbwallet.NewTrezorHubWithHID()
+		if err != nil {
+			hub, err = usbwallet.NewTrezorHubWithWebUSB()
+		}
+	} else {
+		hub, err = usbwallet.NewLedgerHub()
+	}
 	if err != nil {
-		return nil, fmt.Errorf("error starting ledger: %w", err)
+		return nil, "", fmt.Errorf("error starting %s: %w", device, err)
 	}
-	wallets := ledgerHub.Wallets()
+
+	wallets := hub.Wallets()
 	if len(wallets) == 0 {
-		return nil, fmt.Errorf("no ledgers found, please connect your ledger")
+		return nil, "", fmt.Errorf("no %ss found, please connect your %s", device, device)
 	} else if len(wallets) > 1 {
-		return nil, fmt.Errorf("multiple ledgers found, please use one ledger at a time")
+		return nil, "", fmt.Errorf("multiple %ss found, please use one %s at a time", device, device)
 	}
 	wallet := wallets[0]
 	if err := wallet.Open(""); err != nil {
-		return nil, fmt.Errorf("error opening ledger (have you unlocked?): %w", err)
+		return nil, "", fmt.Errorf("error opening %s (have you unlocked?): %w", device, err)
 	}
+	return wallet, device, nil
+}
+
+// openHubWallet derives path on an already-opened hardware wallet.
+func openHubWallet(wallet accounts.Wallet, device string, path accounts.DerivationPath) (signer, error) {
 	account, err := wallet.Derive(path, true)
 	if err != nil {
-		return nil, fmt.Errorf("error deriving ledger account: %w", err)
+		return nil, fmt.Errorf("error deriving %s account: %w", device, err)
 	}
 	return &walletSigner{
 		wallet:  wallet,
@@ -115,9 +459,66 @@ func createSigner(privateKey, mnemonic, hdPath string) (signer, error) {
 	}, nil
 }
 
+// openKeystore prompts for a passphrase and decrypts a V3 keystore JSON
+// file, returning an ecdsaSigner wrapping the recovered private key.
+func openKeystore(file string) (signer, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading keystore file: %w", err)
+	}
+
+	passphrase, err := readPassphraseFromTTY("Keystore passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := keystore.DecryptKey(raw, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting keystore: %w", err)
+	}
+	return &ecdsaSigner{key.PrivateKey}, nil
+}
+
+// readPassphraseFromTTY prompts on and reads from the controlling terminal
+// directly, rather than stdin, since stdin is reserved for the piped
+// hash/typed-data payload this tool signs.
+func readPassphraseFromTTY(prompt string) (string, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return "", fmt.Errorf("error opening /dev/tty to prompt for passphrase: %w", err)
+	}
+	defer tty.Close()
+
+	fmt.Fprint(tty, prompt)
+	passphrase, err := term.ReadPassword(int(tty.Fd()))
+	fmt.Fprintln(tty)
+	if err != nil {
+		return "", fmt.Errorf("error reading passphrase: %w", err)
+	}
+	return string(passphrase), nil
+}
+
+// openClefSigner connects to a remote clef daemon over JSON-RPC/IPC and
+// signs through its single managed account, so clef's rules and audit log
+// govern every request instead of this tool handling keys directly.
+func openClefSigner(endpoint string) (signer, error) {
+	ext, err := external.NewExternalSigner(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to clef at %s: %w", endpoint, err)
+	}
+	accts := ext.Accounts()
+	if len(accts) == 0 {
+		return nil, fmt.Errorf("clef at %s reports no accounts", endpoint)
+	} else if len(accts) > 1 {
+		return nil, fmt.Errorf("clef at %s reports multiple accounts, please configure it to expose one", endpoint)
+	}
+	return &clefSigner{ext: ext, account: accts[0]}, nil
+}
+
 type signer interface {
 	address() common.Address
 	sign([]byte) ([]byte, error)
+	signTypedData(*apitypes.TypedData) ([]byte, error)
 }
 
 type ecdsaSigner struct {
@@ -129,12 +530,15 @@ func (s *ecdsaSigner) address() common.Address {
 }
 
 func (s *ecdsaSigner) sign(data []byte) ([]byte, error) {
-	sig, err := crypto.Sign(crypto.Keccak256(data), s.PrivateKey)
+	return hdwallet.SignDigest(s.PrivateKey, crypto.Keccak256(data))
+}
+
+func (s *ecdsaSigner) signTypedData(typedData *apitypes.TypedData) ([]byte, error) {
+	_, _, digest, err := hdwallet.HashTypedData(typedData)
 	if err != nil {
 		return nil, err
 	}
-	sig[crypto.RecoveryIDOffset] += 27
-	return sig, err
+	return hdwallet.SignDigest(s.PrivateKey, digest)
 }
 
 type walletSigner struct {
@@ -150,39 +554,56 @@ func (s *walletSigner) sign(data []byte) ([]byte, error) {
 	return s.wallet.SignData(s.account, accounts.MimetypeTypedData, data)
 }
 
-func derivePrivateKey(mnemonic string, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
-	// Parse the seed string into the master BIP32 key.
-	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+func (s *walletSigner) signTypedData(typedData *apitypes.TypedData) ([]byte, error) {
+	domainSeparator, messageHash, _, err := hdwallet.HashTypedData(typedData)
 	if err != nil {
 		return nil, err
 	}
+	// SignData hashes its input itself and only takes the structured EIP-712
+	// path for the 66-byte "\x19\x01" || domainSeparator || messageHash form.
+	rawData := append([]byte("\x19\x01"), append(domainSeparator, messageHash...)...)
+	return s.wallet.SignData(s.account, accounts.MimetypeTypedData, rawData)
+}
+
+type clefSigner struct {
+	ext     *external.ExternalSigner
+	account accounts.Account
+
+	sequenceNumber uint64
+}
 
-	privKey, err := hdkeychain.NewMaster(seed, fakeNetworkParams{})
+func (s *clefSigner) address() common.Address {
+	return s.account.Address
+}
+
+func (s *clefSigner) sign(data []byte) ([]byte, error) {
+	sig, err := s.ext.SignData(s.account, accounts.MimetypeTypedData, data)
 	if err != nil {
 		return nil, err
 	}
+	s.sequenceNumber = atomic.AddUint64(&clefRequestSequence, 1)
+	return sig, nil
+}
 
-	for _, child := range path {
-		privKey, err = privKey.Child(child)
-		if err != nil {
-			return nil, err
-		}
+func (s *clefSigner) signTypedData(typedData *apitypes.TypedData) ([]byte, error) {
+	_, _, digest, err := hdwallet.HashTypedData(typedData)
+	if err != nil {
+		return nil, err
 	}
-
-	rawPrivKey, err := privKey.SerializedPrivKey()
+	sig, err := s.ext.SignData(s.account, accounts.MimetypeTypedData, digest)
 	if err != nil {
 		return nil, err
 	}
-
-	return crypto.ToECDSA(rawPrivKey)
+	s.sequenceNumber = atomic.AddUint64(&clefRequestSequence, 1)
+	return sig, nil
 }
 
-type fakeNetworkParams struct{}
-
-func (f fakeNetworkParams) HDPrivKeyVersion() [4]byte {
-	return [4]byte{}
+func (s *clefSigner) lastSequenceNumber() (uint64, bool) {
+	return s.sequenceNumber, s.sequenceNumber != 0
 }
 
-func (f fakeNetworkParams) HDPubKeyVersion() [4]byte {
-	return [4]byte{}
-}
\ No newline at end of file
+// clefRequestSequence hands out a local, process-global sequence number per
+// clef request, since external.ExternalSigner does not expose clef's own
+// JSON-RPC request ID to callers; it is not clef's ID and cannot be
+// cross-referenced against clef's audit log.
+var clefRequestSequence uint64
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/maurelian/contracts-1/internal/hdwallet"
+)
+
+func mustParsePath(t *testing.T, p string) accounts.DerivationPath {
+	t.Helper()
+	path, err := accounts.ParseDerivationPath(p)
+	if err != nil {
+		t.Fatalf("ParseDerivationPath(%q) error = %v", p, err)
+	}
+	return path
+}
+
+func TestExpandHDPathsAlreadyExpanded(t *testing.T) {
+	paths := []accounts.DerivationPath{
+		mustParsePath(t, "m/44'/60'/0'/0/0"),
+		mustParsePath(t, "m/44'/60'/0'/0/1"),
+	}
+	got, err := expandHDPaths(paths, 2)
+	if err != nil {
+		t.Fatalf("expandHDPaths() error = %v", err)
+	}
+	if len(got) != 2 || got[0].String() != paths[0].String() || got[1].String() != paths[1].String() {
+		t.Errorf("expandHDPaths() = %v, want unchanged %v", got, paths)
+	}
+}
+
+func TestExpandHDPathsIncrementsFinalComponent(t *testing.T) {
+	base := mustParsePath(t, "m/44'/60'/0'/0/5")
+	got, err := expandHDPaths([]accounts.DerivationPath{base}, 3)
+	if err != nil {
+		t.Fatalf("expandHDPaths() error = %v", err)
+	}
+	want := []string{"m/44'/60'/0'/0/5", "m/44'/60'/0'/0/6", "m/44'/60'/0'/0/7"}
+	if len(got) != len(want) {
+		t.Fatalf("expandHDPaths() returned %d paths, want %d", len(got), len(want))
+	}
+	for i, p := range got {
+		if p.String() != want[i] {
+			t.Errorf("expandHDPaths()[%d] = %s, want %s", i, p, want[i])
+		}
+	}
+}
+
+func TestExpandHDPathsRejectsBareMasterPath(t *testing.T) {
+	// ParseDerivationPath rejects "m" itself, so build the empty path directly.
+	base := accounts.DerivationPath{}
+	if _, err := expandHDPaths([]accounts.DerivationPath{base}, 3); err == nil {
+		t.Fatal("expandHDPaths() with a bare \"m\" path returned no error, want a fatal error instead of a panic")
+	}
+}
+
+func TestExpandHDPathsRejectsMismatchedCount(t *testing.T) {
+	paths := []accounts.DerivationPath{
+		mustParsePath(t, "m/44'/60'/0'/0/0"),
+		mustParsePath(t, "m/44'/60'/0'/0/1"),
+	}
+	if _, err := expandHDPaths(paths, 3); err == nil {
+		t.Fatal("expandHDPaths() with 2 paths and n=3 returned no error, want an error")
+	}
+}
+
+func TestPackSafeSignaturesSortsByAddress(t *testing.T) {
+	lo := common.HexToAddress("0x1000000000000000000000000000000000000000")
+	hi := common.HexToAddress("0x9000000000000000000000000000000000000000")
+
+	sigLo := make([]byte, 65)
+	sigLo[crypto.RecoveryIDOffset] = 27
+	sigHi := make([]byte, 65)
+	sigHi[crypto.RecoveryIDOffset] = 28
+
+	// Entries are passed in descending address order to verify packing sorts
+	// them ascending, as Safe's checkNSignatures requires.
+	packed, manifest := packSafeSignatures([]signedEntry{
+		{address: hi, sig: sigHi},
+		{address: lo, sig: sigLo},
+	})
+
+	if len(packed) != 130 {
+		t.Fatalf("len(packed) = %d, want 130", len(packed))
+	}
+	if got, want := packed[crypto.RecoveryIDOffset], byte(27); got != want {
+		t.Errorf("packed[lo].recoveryID = %d, want %d", got, want)
+	}
+	if got, want := packed[65+crypto.RecoveryIDOffset], byte(28); got != want {
+		t.Errorf("packed[hi].recoveryID = %d, want %d", got, want)
+	}
+
+	if want := "0x" + hex.EncodeToString(sigLo); manifest[lo.String()] != want {
+		t.Errorf("manifest[%s] = %s, want %s", lo, manifest[lo.String()], want)
+	}
+	if len(manifest) != 2 {
+		t.Fatalf("len(manifest) = %d, want 2", len(manifest))
+	}
+}
+
+func TestPackSafeSignaturesDoesNotMutateInput(t *testing.T) {
+	addr := common.HexToAddress("0x1000000000000000000000000000000000000000")
+	sig := make([]byte, 65)
+	sig[crypto.RecoveryIDOffset] = 27
+	entries := []signedEntry{{address: addr, sig: sig}}
+
+	packSafeSignatures(entries)
+
+	if entries[0].sig[crypto.RecoveryIDOffset] != 27 {
+		t.Errorf("packSafeSignatures() mutated the caller's signature in place")
+	}
+}
+
+// TestPackSafeSignaturesRecoversToUnprefixedDigest guards against
+// reintroducing the eth_sign-style +4 recovery-id bump: Safe's
+// checkNSignatures treats v>30 as "re-hash with the eth_sign prefix before
+// ecrecover", but every signer in this tool signs the raw/EIP-712 digest
+// directly, so a packed signature must recover the signer's address from
+// that same un-prefixed digest with a plain v=27/28.
+func TestPackSafeSignaturesRecoversToUnprefixedDigest(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	digest := crypto.Keccak256([]byte("packSafeSignatures test message"))
+	sig, err := hdwallet.SignDigest(key, digest)
+	if err != nil {
+		t.Fatalf("SignDigest() error = %v", err)
+	}
+
+	packed, _ := packSafeSignatures([]signedEntry{{address: addr, sig: sig}})
+	if len(packed) != 65 {
+		t.Fatalf("len(packed) = %d, want 65", len(packed))
+	}
+
+	recoverable := append([]byte{}, packed...)
+	recoverable[crypto.RecoveryIDOffset] -= 27
+	pubKeyBytes, err := crypto.Ecrecover(digest, recoverable)
+	if err != nil {
+		t.Fatalf("Ecrecover() error = %v", err)
+	}
+	pubKey, err := crypto.UnmarshalPubkey(pubKeyBytes)
+	if err != nil {
+		t.Fatalf("UnmarshalPubkey() error = %v", err)
+	}
+	if got := crypto.PubkeyToAddress(*pubKey); got != addr {
+		t.Errorf("packed signature recovered to %s over the un-prefixed digest, want %s", got, addr)
+	}
+}
@@ -0,0 +1,161 @@
+// Package hdwallet implements a minimal BIP-39/BIP-32 HD wallet: parse a
+// mnemonic (with an optional passphrase) once, cache the resulting BIP-32
+// master key, and derive as many child accounts from it as needed without
+// reparsing the mnemonic (and rerunning its PBKDF2 seed derivation) per
+// account.
+package hdwallet
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+
+	"github.com/decred/dcrd/hdkeychain/v3"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// DefaultBasePath is the BIP-44 Ethereum base path below which an account
+// index is appended to enumerate accounts, e.g. via Accounts.
+const DefaultBasePath = "m/44'/60'/0'/0"
+
+// Wallet caches a mnemonic's BIP-32 master key and derives child accounts
+// from it on demand.
+type Wallet struct {
+	master *hdkeychain.ExtendedKey
+}
+
+// New parses mnemonic, optionally strengthened with a BIP-39 passphrase,
+// into a BIP-32 master key and returns a Wallet ready to derive child
+// accounts from it.
+func New(mnemonic, passphrase string) (*Wallet, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("error deriving seed from mnemonic: %w", err)
+	}
+
+	master, err := hdkeychain.NewMaster(seed, fakeNetworkParams{})
+	if err != nil {
+		return nil, fmt.Errorf("error deriving master key: %w", err)
+	}
+
+	return &Wallet{master: master}, nil
+}
+
+// Derive walks path from the cached master key and returns the resulting
+// private key.
+func (w *Wallet) Derive(path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	key := w.master
+	for _, child := range path {
+		var err error
+		key, err = key.Child(child)
+		if err != nil {
+			return nil, fmt.Errorf("error deriving path %v: %w", path, err)
+		}
+	}
+
+	rawPrivKey, err := key.SerializedPrivKey()
+	if err != nil {
+		return nil, err
+	}
+	return crypto.ToECDSA(rawPrivKey)
+}
+
+// Account is an HD-derived address paired with the path it came from.
+type Account struct {
+	Path    accounts.DerivationPath
+	Address common.Address
+}
+
+// Accounts derives the first n accounts under base, appending index i as a
+// new final component (following BIP-44's "m/44'/60'/0'/0/i" convention), so an
+// operator can pick the right index before signing.
+func (w *Wallet) Accounts(base accounts.DerivationPath, n int) ([]Account, error) {
+	out := make([]Account, 0, n)
+	for i := 0; i < n; i++ {
+		path := append(accounts.DerivationPath{}, base...)
+		path = append(path, uint32(i))
+
+		key, err := w.Derive(path)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, Account{
+			Path:    path,
+			Address: crypto.PubkeyToAddress(key.PublicKey),
+		})
+	}
+	return out, nil
+}
+
+// SignHash derives the private key at path and signs Keccak256(hash), applying the
+// same +27 recovery-id adjustment the rest of this tool's signers use.
+func (w *Wallet) SignHash(path accounts.DerivationPath, hash []byte) ([]byte, error) {
+	key, err := w.Derive(path)
+	if err != nil {
+		return nil, err
+	}
+	return SignDigest(key, crypto.Keccak256(hash))
+}
+
+// SignTypedData derives the private key at path and signs the EIP-712
+// digest of typedData.
+func (w *Wallet) SignTypedData(path accounts.DerivationPath, typedData *apitypes.TypedData) ([]byte, error) {
+	key, err := w.Derive(path)
+	if err != nil {
+		return nil, err
+	}
+	_, _, digest, err := HashTypedData(typedData)
+	if err != nil {
+		return nil, err
+	}
+	return SignDigest(key, digest)
+}
+
+// HashTypedData computes the EIP-712 domain separator, message hash, and
+// final "\x19\x01"-prefixed digest for a typed-data payload, mirroring the
+// hashing go-ethereum's apitypes.TypedDataAndHash performs.
+// It is exported so callers can both display the intermediate hashes (e.g.
+// for a Ledger's domain-aware confirmation screen) and sign the digest
+// without duplicating this computation themselves.
+func HashTypedData(typedData *apitypes.TypedData) (domainSeparator, messageHash, digest []byte, err error) {
+	domainSeparator, err = typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error hashing EIP712Domain: %w", err)
+	}
+
+	messageHash, err = typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error hashing %s: %w", typedData.PrimaryType, err)
+	}
+
+	rawData := append([]byte("\x19\x01"), append(domainSeparator, messageHash...)...)
+	digest = crypto.Keccak256(rawData)
+	return domainSeparator, messageHash, digest, nil
+}
+
+// SignDigest signs digest with key and bumps the recovery id by 27, the
+// convention go-ethereum's eth_sign-family signers use and that this
+// tool's callers (Safe, etc.) expect. It is exported so callers holding a
+// raw private key (e.g. from --private-key) can share this logic with
+// Wallet's HD-derived signing instead of reimplementing it.
+func SignDigest(key *ecdsa.PrivateKey, digest []byte) ([]byte, error) {
+	sig, err := crypto.Sign(digest, key)
+	if err != nil {
+		return nil, err
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return sig, nil
+}
+
+type fakeNetworkParams struct{}
+
+func (f fakeNetworkParams) HDPrivKeyVersion() [4]byte {
+	return [4]byte{}
+}
+
+func (f fakeNetworkParams) HDPubKeyVersion() [4]byte {
+	return [4]byte{}
+}
@@ -0,0 +1,176 @@
+package hdwallet
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testMnemonic is the all-"abandon" BIP-39 test vector used throughout the
+// ecosystem (e.g. ethereumjs-wallet, MyCrypto) for deterministic HD wallet
+// tests, paired with its well-known first Ethereum address under
+// m/44'/60'/0'/0/0.
+const (
+	testMnemonic     = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	testFirstAddress = "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"
+)
+
+func TestWalletDeriveKnownVector(t *testing.T) {
+	w, err := New(testMnemonic, "")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	base, err := accounts.ParseDerivationPath(DefaultBasePath)
+	if err != nil {
+		t.Fatalf("ParseDerivationPath() error = %v", err)
+	}
+
+	accts, err := w.Accounts(base, 1)
+	if err != nil {
+		t.Fatalf("Accounts() error = %v", err)
+	}
+	if got := accts[0].Address.String(); got != testFirstAddress {
+		t.Errorf("Accounts()[0].Address = %s, want %s", got, testFirstAddress)
+	}
+}
+
+func TestWalletDeriveIsDeterministic(t *testing.T) {
+	w, err := New(testMnemonic, "")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	base, err := accounts.ParseDerivationPath(DefaultBasePath)
+	if err != nil {
+		t.Fatalf("ParseDerivationPath() error = %v", err)
+	}
+	path := append(accounts.DerivationPath{}, base...)
+	path = append(path, 0)
+
+	key1, err := w.Derive(path)
+	if err != nil {
+		t.Fatalf("Derive() error = %v", err)
+	}
+	key2, err := w.Derive(path)
+	if err != nil {
+		t.Fatalf("Derive() error = %v", err)
+	}
+	if key1.D.Cmp(key2.D) != 0 {
+		t.Errorf("Derive(%v) returned different keys on repeated calls", path)
+	}
+}
+
+func TestWalletPassphraseChangesDerivedKey(t *testing.T) {
+	withPassphrase, err := New(testMnemonic, "some-passphrase")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	withoutPassphrase, err := New(testMnemonic, "")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	base, err := accounts.ParseDerivationPath(DefaultBasePath)
+	if err != nil {
+		t.Fatalf("ParseDerivationPath() error = %v", err)
+	}
+
+	a, err := withPassphrase.Accounts(base, 1)
+	if err != nil {
+		t.Fatalf("Accounts() error = %v", err)
+	}
+	b, err := withoutPassphrase.Accounts(base, 1)
+	if err != nil {
+		t.Fatalf("Accounts() error = %v", err)
+	}
+	if a[0].Address == b[0].Address {
+		t.Errorf("expected different addresses for different BIP-39 passphrases, got the same: %s", a[0].Address)
+	}
+}
+
+func TestWalletAccountsEnumeratesIndices(t *testing.T) {
+	w, err := New(testMnemonic, "")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	base, err := accounts.ParseDerivationPath(DefaultBasePath)
+	if err != nil {
+		t.Fatalf("ParseDerivationPath() error = %v", err)
+	}
+
+	const n = 3
+	accts, err := w.Accounts(base, n)
+	if err != nil {
+		t.Fatalf("Accounts() error = %v", err)
+	}
+	if len(accts) != n {
+		t.Fatalf("Accounts() returned %d accounts, want %d", len(accts), n)
+	}
+
+	seen := make(map[string]bool, n)
+	for i, acct := range accts {
+		wantPath := append(accounts.DerivationPath{}, base...)
+		wantPath = append(wantPath, uint32(i))
+		if acct.Path.String() != wantPath.String() {
+			t.Errorf("accts[%d].Path = %s, want %s", i, acct.Path, wantPath)
+		}
+		if seen[acct.Address.String()] {
+			t.Errorf("accts[%d].Address = %s duplicates an earlier account", i, acct.Address)
+		}
+		seen[acct.Address.String()] = true
+	}
+}
+
+func TestWalletSignHashRecoversToDerivedAddress(t *testing.T) {
+	w, err := New(testMnemonic, "")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	base, err := accounts.ParseDerivationPath(DefaultBasePath)
+	if err != nil {
+		t.Fatalf("ParseDerivationPath() error = %v", err)
+	}
+	accts, err := w.Accounts(base, 1)
+	if err != nil {
+		t.Fatalf("Accounts() error = %v", err)
+	}
+	path := accts[0].Path
+	want := accts[0].Address
+
+	hash := make([]byte, 32)
+	for i := range hash {
+		hash[i] = byte(i)
+	}
+
+	sig, err := w.SignHash(path, hash)
+	if err != nil {
+		t.Fatalf("SignHash() error = %v", err)
+	}
+	if got := recoverAddress(t, crypto.Keccak256(hash), sig); got != want {
+		t.Errorf("SignHash() recovered address = %s, want %s", got, want)
+	}
+}
+
+// recoverAddress undoes the +27 recovery-id adjustment SignDigest applies
+// and recovers the signer address from sig over digest, mirroring the
+// "verify" subcommand's recovery logic in cmd/sign.
+func recoverAddress(t *testing.T, digest, sig []byte) common.Address {
+	t.Helper()
+	recoverable := append([]byte{}, sig...)
+	recoverable[crypto.RecoveryIDOffset] -= 27
+
+	pubKeyBytes, err := crypto.Ecrecover(digest, recoverable)
+	if err != nil {
+		t.Fatalf("Ecrecover() error = %v", err)
+	}
+	pubKey, err := crypto.UnmarshalPubkey(pubKeyBytes)
+	if err != nil {
+		t.Fatalf("UnmarshalPubkey() error = %v", err)
+	}
+	return crypto.PubkeyToAddress(*pubKey)
+}